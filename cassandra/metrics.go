@@ -0,0 +1,177 @@
+package cassandra
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	log "gerrit.wikimedia.org/r/mediawiki/services/servicelib-golang/logger"
+
+	"github.com/gocql/gocql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsQueryObserver is a [QueryObserver] implementation for [GoCQL].  It records
+// per-statement latency (partitioned by keyspace and outcome) into a Prometheus
+// histogram, increments a counter of executed queries, and logs any query whose observed
+// duration exceeds SlowQueryThreshold at WARNING level via [servicelib-golang/logger].
+//
+// Queries are not partitioned by consistency level: neither gocql.ObservedQuery nor
+// gocql.ObservedBatch (see MetricsBatchObserver) carry the consistency a query/batch was
+// executed at, so there is nothing to label with here. This was confirmed against gocql's
+// public ObservedQuery/ObservedBatch struct definitions; Should a future gocql release add
+// a consistency field to either, add it alongside keyspace and outcome below.
+//
+// Example usage:
+//
+//	observer, _ := cassandra.NewMetricsQueryObserver(logger, prometheus.DefaultRegisterer, 250*time.Millisecond)
+//	cluster := gocql.NewCluster("192.168.1.1", "192.168.1.2", "192.168.1.3")
+//	cluster.QueryObserver = observer
+//
+// [QueryObserver]: https://pkg.go.dev/github.com/gocql/gocql?utm_source=godoc#QueryObserver
+// [GoCQL]: https://github.com/gocql/gocql
+// [servicelib-golang/logger]: https://gerrit.wikimedia.org/g/mediawiki/services/servicelib-golang
+type MetricsQueryObserver struct {
+	Logger             *log.Logger
+	SlowQueryThreshold time.Duration
+
+	queriesTotal *prometheus.CounterVec
+	latency      *prometheus.HistogramVec
+}
+
+// NewMetricsQueryObserver creates a MetricsQueryObserver, registering its metrics (a count
+// of executed queries, and a histogram of query latencies, both partitioned by keyspace
+// and outcome) on registerer.
+func NewMetricsQueryObserver(logger *log.Logger, registerer prometheus.Registerer, slowQueryThreshold time.Duration) (*MetricsQueryObserver, error) {
+	var queriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cassandra_queries_total",
+			Help: "Count of CQL queries executed, partitioned by keyspace and outcome.",
+		},
+		[]string{"keyspace", "success"},
+	)
+
+	var latency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "cassandra_query_duration_seconds",
+			Help: "A histogram of CQL query latencies, partitioned by keyspace and outcome.",
+		},
+		[]string{"keyspace", "success"},
+	)
+
+	if err := registerer.Register(queriesTotal); err != nil {
+		return nil, err
+	}
+
+	if err := registerer.Register(latency); err != nil {
+		return nil, err
+	}
+
+	return &MetricsQueryObserver{
+		Logger:             logger,
+		SlowQueryThreshold: slowQueryThreshold,
+		queriesTotal:       queriesTotal,
+		latency:            latency,
+	}, nil
+}
+
+// ObserveQuery implements gocql.QueryObserver.
+func (o *MetricsQueryObserver) ObserveQuery(ctx context.Context, observed gocql.ObservedQuery) {
+	var success = strconv.FormatBool(observed.Err == nil)
+	var duration = observed.End.Sub(observed.Start)
+
+	o.queriesTotal.WithLabelValues(observed.Keyspace, success).Inc()
+	o.latency.WithLabelValues(observed.Keyspace, success).Observe(duration.Seconds())
+
+	if o.SlowQueryThreshold > 0 && duration > o.SlowQueryThreshold {
+		var host interface{} = "unknown"
+		if observed.Host != nil {
+			host = observed.Host.ConnectAddress()
+		}
+		o.Logger.Warning("Cassandra: Slow query (%s) on %s (keyspace: %s): %s", duration, host, observed.Keyspace, observed.Statement)
+	}
+}
+
+// MetricsBatchObserver is a [BatchObserver] implementation for [GoCQL], analogous to
+// MetricsQueryObserver, but for batch statements. As with MetricsQueryObserver, there is no
+// consistency label, since gocql.ObservedBatch doesn't carry one either (see
+// MetricsQueryObserver for details).
+//
+// [BatchObserver]: https://pkg.go.dev/github.com/gocql/gocql?utm_source=godoc#BatchObserver
+type MetricsBatchObserver struct {
+	Logger             *log.Logger
+	SlowQueryThreshold time.Duration
+
+	batchesTotal *prometheus.CounterVec
+	latency      *prometheus.HistogramVec
+}
+
+// NewMetricsBatchObserver creates a MetricsBatchObserver, registering its metrics (a count
+// of executed batches, and a histogram of batch latencies, both partitioned by keyspace
+// and outcome) on registerer.
+func NewMetricsBatchObserver(logger *log.Logger, registerer prometheus.Registerer, slowQueryThreshold time.Duration) (*MetricsBatchObserver, error) {
+	var batchesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cassandra_batches_total",
+			Help: "Count of CQL batches executed, partitioned by keyspace and outcome.",
+		},
+		[]string{"keyspace", "success"},
+	)
+
+	var latency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "cassandra_batch_duration_seconds",
+			Help: "A histogram of CQL batch latencies, partitioned by keyspace and outcome.",
+		},
+		[]string{"keyspace", "success"},
+	)
+
+	if err := registerer.Register(batchesTotal); err != nil {
+		return nil, err
+	}
+
+	if err := registerer.Register(latency); err != nil {
+		return nil, err
+	}
+
+	return &MetricsBatchObserver{
+		Logger:             logger,
+		SlowQueryThreshold: slowQueryThreshold,
+		batchesTotal:       batchesTotal,
+		latency:            latency,
+	}, nil
+}
+
+// ObserveBatch implements gocql.BatchObserver.
+func (o *MetricsBatchObserver) ObserveBatch(ctx context.Context, observed gocql.ObservedBatch) {
+	var success = strconv.FormatBool(observed.Err == nil)
+	var duration = observed.End.Sub(observed.Start)
+
+	o.batchesTotal.WithLabelValues(observed.Keyspace, success).Inc()
+	o.latency.WithLabelValues(observed.Keyspace, success).Observe(duration.Seconds())
+
+	if o.SlowQueryThreshold > 0 && duration > o.SlowQueryThreshold {
+		o.Logger.Warning("Cassandra: Slow batch (%s) on keyspace %s (%d statements)", duration, observed.Keyspace, len(observed.Statements))
+	}
+}
+
+// Combined wires a LoggingConnectObserver, a MetricsQueryObserver, and a
+// MetricsBatchObserver onto cluster in one call, registering metrics on registerer, and
+// logging query/batch statements exceeding slowQueryThreshold via logger.
+func Combined(cluster *gocql.ClusterConfig, logger *log.Logger, registerer prometheus.Registerer, slowQueryThreshold time.Duration) error {
+	cluster.ConnectObserver = &LoggingConnectObserver{Logger: logger}
+
+	queryObserver, err := NewMetricsQueryObserver(logger, registerer, slowQueryThreshold)
+	if err != nil {
+		return err
+	}
+	cluster.QueryObserver = queryObserver
+
+	batchObserver, err := NewMetricsBatchObserver(logger, registerer, slowQueryThreshold)
+	if err != nil {
+		return err
+	}
+	cluster.BatchObserver = batchObserver
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package cassandra
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	log "gerrit.wikimedia.org/r/mediawiki/services/servicelib-golang/logger"
+
+	"github.com/gocql/gocql"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWriter struct {
+	data []byte
+}
+
+func (m *mockWriter) Write(data []byte) (n int, err error) {
+	m.data = append(m.data, data...)
+	return len(data), nil
+}
+
+func counterValue(t *testing.T, c *prometheus.CounterVec, labels prometheus.Labels) float64 {
+	t.Helper()
+	var metric dto.Metric
+	require.Nil(t, c.With(labels).Write(&metric))
+	return metric.GetCounter().GetValue()
+}
+
+func histogramCount(t *testing.T, h *prometheus.HistogramVec, labels prometheus.Labels) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	require.Nil(t, h.With(labels).(prometheus.Histogram).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestNewMetricsQueryObserver(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	writer := &mockWriter{}
+	logger, _ := log.NewLogger(writer, "cassandratest", log.DEBUG)
+
+	observer, err := NewMetricsQueryObserver(logger, registry, 10*time.Millisecond)
+	require.Nil(t, err)
+
+	t.Run("Records successful queries", func(t *testing.T) {
+		observer.ObserveQuery(context.Background(), gocql.ObservedQuery{
+			Keyspace:  "myks",
+			Statement: "SELECT * FROM foo",
+			Start:     time.Unix(0, 0),
+			End:       time.Unix(0, 0),
+			Host:      (&gocql.HostInfo{}).SetConnectAddress(net.IPv4(127, 0, 0, 1)),
+		})
+
+		assert.Equal(t, float64(1), counterValue(t, observer.queriesTotal, prometheus.Labels{"keyspace": "myks", "success": "true"}))
+		assert.Equal(t, uint64(1), histogramCount(t, observer.latency, prometheus.Labels{"keyspace": "myks", "success": "true"}))
+	})
+
+	t.Run("Records failed queries separately", func(t *testing.T) {
+		observer.ObserveQuery(context.Background(), gocql.ObservedQuery{
+			Keyspace: "myks",
+			Start:    time.Unix(0, 0),
+			End:      time.Unix(0, 0),
+			Host:     (&gocql.HostInfo{}).SetConnectAddress(net.IPv4(127, 0, 0, 1)),
+			Err:      context.DeadlineExceeded,
+		})
+
+		assert.Equal(t, float64(1), counterValue(t, observer.queriesTotal, prometheus.Labels{"keyspace": "myks", "success": "false"}))
+	})
+
+	t.Run("Logs queries exceeding the slow query threshold", func(t *testing.T) {
+		writer.data = nil
+
+		observer.ObserveQuery(context.Background(), gocql.ObservedQuery{
+			Keyspace:  "myks",
+			Statement: "SELECT * FROM slow",
+			Start:     time.Unix(0, 0),
+			End:       time.Unix(0, 0).Add(time.Second),
+			Host:      (&gocql.HostInfo{}).SetConnectAddress(net.IPv4(127, 0, 0, 1)),
+		})
+
+		assert.Contains(t, string(writer.data), "Slow query")
+		assert.Contains(t, string(writer.data), "SELECT * FROM slow")
+	})
+
+	t.Run("Does not log queries under the slow query threshold", func(t *testing.T) {
+		writer.data = nil
+
+		observer.ObserveQuery(context.Background(), gocql.ObservedQuery{
+			Keyspace: "myks",
+			Start:    time.Unix(0, 0),
+			End:      time.Unix(0, 0),
+			Host:     (&gocql.HostInfo{}).SetConnectAddress(net.IPv4(127, 0, 0, 1)),
+		})
+
+		assert.Equal(t, 0, len(writer.data))
+	})
+
+	t.Run("Logs a slow query with a nil Host instead of panicking", func(t *testing.T) {
+		writer.data = nil
+
+		assert.NotPanics(t, func() {
+			observer.ObserveQuery(context.Background(), gocql.ObservedQuery{
+				Keyspace:  "myks",
+				Statement: "SELECT * FROM slow",
+				Start:     time.Unix(0, 0),
+				End:       time.Unix(0, 0).Add(time.Second),
+			})
+		})
+
+		assert.Contains(t, string(writer.data), "Slow query")
+	})
+}
+
+func TestNewMetricsBatchObserver(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	writer := &mockWriter{}
+	logger, _ := log.NewLogger(writer, "cassandratest", log.DEBUG)
+
+	observer, err := NewMetricsBatchObserver(logger, registry, 10*time.Millisecond)
+	require.Nil(t, err)
+
+	observer.ObserveBatch(context.Background(), gocql.ObservedBatch{
+		Keyspace:   "myks",
+		Statements: []string{"INSERT INTO foo ...", "INSERT INTO bar ..."},
+		Start:      time.Unix(0, 0),
+		End:        time.Unix(0, 0).Add(time.Second),
+	})
+
+	assert.Equal(t, float64(1), counterValue(t, observer.batchesTotal, prometheus.Labels{"keyspace": "myks", "success": "true"}))
+	assert.Contains(t, string(writer.data), "Slow batch")
+}
+
+func TestCombined(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	logger, _ := log.NewLogger(&mockWriter{}, "cassandratest", log.DEBUG)
+	cluster := gocql.NewCluster("127.0.0.1")
+
+	err := Combined(cluster, logger, registry, 10*time.Millisecond)
+	require.Nil(t, err)
+
+	assert.NotNil(t, cluster.ConnectObserver)
+	assert.NotNil(t, cluster.QueryObserver)
+	assert.NotNil(t, cluster.BatchObserver)
+}
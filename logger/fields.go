@@ -0,0 +1,131 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import "fmt"
+
+// With returns a copy of the RequestScopedLogger with key/val added to its accumulated
+// structured fields (logged under the LogMessage "labels" key). The receiver is left
+// unmodified, so that the fields added by one call site are not visible to others sharing
+// the same RequestScopedLogger.
+func (s *RequestScopedLogger) With(key string, val interface{}) *RequestScopedLogger {
+	return s.WithFields(map[string]interface{}{key: val})
+}
+
+// WithFields returns a copy of the RequestScopedLogger with fields merged into its
+// accumulated structured fields. The receiver is left unmodified.
+func (s *RequestScopedLogger) WithFields(fields map[string]interface{}) *RequestScopedLogger {
+	var merged = make(map[string]interface{}, len(s.fields)+len(fields))
+	for k, v := range s.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return &RequestScopedLogger{
+		logger:       s.logger,
+		client:       s.client,
+		network:      s.network,
+		trace:        s.trace,
+		span:         s.span,
+		traceContext: s.traceContext,
+		fields:       merged,
+	}
+}
+
+// Debugf logs a printf-style message of severity DEBUG.
+func (s *RequestScopedLogger) Debugf(format string, v ...interface{}) {
+	s.Log(DEBUG, format, v...)
+}
+
+// Infof logs a printf-style message of severity INFO.
+func (s *RequestScopedLogger) Infof(format string, v ...interface{}) {
+	s.Log(INFO, format, v...)
+}
+
+// Warningf logs a printf-style message of severity WARNING.
+func (s *RequestScopedLogger) Warningf(format string, v ...interface{}) {
+	s.Log(WARNING, format, v...)
+}
+
+// Errorf logs a printf-style message of severity ERROR.
+func (s *RequestScopedLogger) Errorf(format string, v ...interface{}) {
+	s.Log(ERROR, format, v...)
+}
+
+// Fatalf logs a printf-style message of severity FATAL.
+func (s *RequestScopedLogger) Fatalf(format string, v ...interface{}) {
+	s.Log(FATAL, format, v...)
+}
+
+// Debugw logs msg at severity DEBUG, merging the given alternating key/value pairs into
+// its structured fields for this message only (see: WithFields).
+func (s *RequestScopedLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	s.logw(DEBUG, msg, keysAndValues...)
+}
+
+// Infow logs msg at severity INFO, merging the given alternating key/value pairs into its
+// structured fields for this message only (see: WithFields).
+func (s *RequestScopedLogger) Infow(msg string, keysAndValues ...interface{}) {
+	s.logw(INFO, msg, keysAndValues...)
+}
+
+// Warningw logs msg at severity WARNING, merging the given alternating key/value pairs
+// into its structured fields for this message only (see: WithFields).
+func (s *RequestScopedLogger) Warningw(msg string, keysAndValues ...interface{}) {
+	s.logw(WARNING, msg, keysAndValues...)
+}
+
+// Errorw logs msg at severity ERROR, merging the given alternating key/value pairs into
+// its structured fields for this message only (see: WithFields).
+func (s *RequestScopedLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	s.logw(ERROR, msg, keysAndValues...)
+}
+
+// Fatalw logs msg at severity FATAL, merging the given alternating key/value pairs into
+// its structured fields for this message only (see: WithFields).
+func (s *RequestScopedLogger) Fatalw(msg string, keysAndValues ...interface{}) {
+	s.logw(FATAL, msg, keysAndValues...)
+}
+
+func (s *RequestScopedLogger) logw(level Level, msg string, keysAndValues ...interface{}) {
+	var scoped = s
+	if len(keysAndValues) > 0 {
+		scoped = s.WithFields(fieldsFromPairs(keysAndValues))
+	}
+	// msg is a literal message, not a format string (see: popular Go loggers' *w methods);
+	// Pass it through "%s" rather than risking Sprintf misinterpreting stray '%' verbs.
+	scoped.Log(level, "%s", msg)
+}
+
+// fieldsFromPairs converts an alternating key/value slice, as accepted by the *w logging
+// methods, into a fields map. A non-string key is formatted with fmt.Sprintf; A trailing
+// key with no matching value is dropped.
+func fieldsFromPairs(keysAndValues []interface{}) map[string]interface{} {
+	var fields = make(map[string]interface{}, len(keysAndValues)/2)
+
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keysAndValues[i])
+		}
+		fields[key] = keysAndValues[i+1]
+	}
+
+	return fields
+}
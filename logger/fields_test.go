@@ -0,0 +1,170 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFields(t *testing.T) {
+	writer, logger := setUp(DEBUG)
+	req := httptest.NewRequest("GET", "/", nil)
+
+	t.Run("With adds a single field", func(t *testing.T) {
+		logger.Request(req).With("user", "nemo").Log(INFO, "hello")
+
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"user": "nemo"}, msg.Labels)
+	})
+
+	t.Run("WithFields merges multiple fields", func(t *testing.T) {
+		logger.Request(req).WithFields(map[string]interface{}{"a": 1.0, "b": "two"}).Log(INFO, "hello")
+
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"a": 1.0, "b": "two"}, msg.Labels)
+	})
+
+	t.Run("With does not mutate the receiver", func(t *testing.T) {
+		base := logger.Request(req)
+		withUser := base.With("user", "nemo")
+
+		base.Log(INFO, "no fields here")
+		baseMsg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Nil(t, baseMsg.Labels)
+
+		withUser.Log(INFO, "has fields")
+		withUserMsg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"user": "nemo"}, withUserMsg.Labels)
+	})
+
+	t.Run("Chained With calls accumulate fields", func(t *testing.T) {
+		logger.Request(req).With("a", 1.0).With("b", 2.0).Log(INFO, "hello")
+
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"a": 1.0, "b": 2.0}, msg.Labels)
+	})
+}
+
+func TestPrintfStyleTerminalMethods(t *testing.T) {
+	writer, logger := setUp(DEBUG)
+	req := httptest.NewRequest("GET", "/", nil)
+
+	testCases := []struct {
+		name  string
+		log   func(s *RequestScopedLogger)
+		level Level
+		want  string
+	}{
+		{"Debugf", func(s *RequestScopedLogger) { s.Debugf("a %s", "debug") }, DEBUG, "a debug"},
+		{"Infof", func(s *RequestScopedLogger) { s.Infof("a %s", "info") }, INFO, "a info"},
+		{"Warningf", func(s *RequestScopedLogger) { s.Warningf("a %s", "warning") }, WARNING, "a warning"},
+		{"Errorf", func(s *RequestScopedLogger) { s.Errorf("a %s", "error") }, ERROR, "a error"},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.name, func(t *testing.T) {
+			tcase.log(logger.Request(req))
+
+			msg, err := writer.ReadMessage()
+			require.Nil(t, err)
+			assert.Equal(t, tcase.want, msg.Message)
+			assert.Equal(t, LevelString(tcase.level), msg.Log.Level)
+		})
+	}
+}
+
+func TestStructuredTerminalMethods(t *testing.T) {
+	writer, logger := setUp(DEBUG)
+	req := httptest.NewRequest("GET", "/", nil)
+
+	t.Run("Infow merges key/value pairs as fields for this message only", func(t *testing.T) {
+		logger.Request(req).Infow("request completed", "status", 200.0, "bytes", 512.0)
+
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, "request completed", msg.Message)
+		assert.Equal(t, LevelString(INFO), msg.Log.Level)
+		assert.Equal(t, map[string]interface{}{"status": 200.0, "bytes": 512.0}, msg.Labels)
+	})
+
+	t.Run("Infow does not leak fields onto the next call", func(t *testing.T) {
+		scoped := logger.Request(req)
+		scoped.Infow("first", "only_here", 1.0)
+		writer.ReadMessage()
+
+		scoped.Infow("second")
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Nil(t, msg.Labels)
+	})
+
+	t.Run("A literal '%' in the message is not treated as a format verb", func(t *testing.T) {
+		logger.Request(req).Infow("memory at 92% full")
+
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, "memory at 92% full", msg.Message)
+	})
+
+	t.Run("Debugw and Warningw and Errorw and Fatalw log at their respective levels", func(t *testing.T) {
+		logger.Request(req).Debugw("debug msg")
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, LevelString(DEBUG), msg.Log.Level)
+
+		logger.Request(req).Warningw("warning msg")
+		msg, err = writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, LevelString(WARNING), msg.Log.Level)
+
+		logger.Request(req).Errorw("error msg")
+		msg, err = writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, LevelString(ERROR), msg.Log.Level)
+
+		logger.Request(req).Fatalw("fatal msg")
+		msg, err = writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Equal(t, LevelString(FATAL), msg.Log.Level)
+	})
+}
+
+func TestFieldsFromPairs(t *testing.T) {
+	t.Run("Pairs become a map", func(t *testing.T) {
+		fields := fieldsFromPairs([]interface{}{"a", 1, "b", "two"})
+		assert.Equal(t, map[string]interface{}{"a": 1, "b": "two"}, fields)
+	})
+
+	t.Run("A trailing key with no value is dropped", func(t *testing.T) {
+		fields := fieldsFromPairs([]interface{}{"a", 1, "dangling"})
+		assert.Equal(t, map[string]interface{}{"a": 1}, fields)
+	})
+
+	t.Run("A non-string key is stringified", func(t *testing.T) {
+		fields := fieldsFromPairs([]interface{}{42, "answer"})
+		assert.Equal(t, map[string]interface{}{"42": "answer"}, fields)
+	})
+}
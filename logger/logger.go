@@ -18,7 +18,6 @@
 package logger
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -40,13 +39,15 @@ const (
 
 // LogMessage represents JSON serializable log messages.
 type LogMessage struct {
-	Timestamp string      `json:"@timestamp"`
-	Message   string      `json:"message"`
-	Client    *ecsClient  `json:"client,omitempty"`
-	Log       ecsLog      `json:"log"`
-	Network   *ecsNetwork `json:"network,omitempty"`
-	Service   ecsService  `json:"service"`
-	Trace     *ecsTrace   `json:"trace,omitempty"`
+	Timestamp string                 `json:"@timestamp"`
+	Message   string                 `json:"message"`
+	Client    *ecsClient             `json:"client,omitempty"`
+	Log       ecsLog                 `json:"log"`
+	Network   *ecsNetwork            `json:"network,omitempty"`
+	Service   ecsService             `json:"service"`
+	Trace     *ecsTrace              `json:"trace,omitempty"`
+	Span      *ecsSpan               `json:"span,omitempty"`
+	Labels    map[string]interface{} `json:"labels,omitempty"`
 }
 
 // Corresponds to https://doc.wikimedia.org/ecs/#ecs-client
@@ -74,15 +75,24 @@ type ecsService struct {
 
 // Corresponds to https://doc.wikimedia.org/ecs/#ecs-tracing
 type ecsTrace struct {
+	ID       string `json:"id,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// Corresponds to https://doc.wikimedia.org/ecs/#ecs-tracing
+type ecsSpan struct {
 	ID string `json:"id,omitempty"`
 }
 
 // RequestScopedLogger formats and delivers a Logger and optional LogMessage attributes.
 type RequestScopedLogger struct {
-	logger  *Logger
-	client  *ecsClient
-	network *ecsNetwork
-	trace   *ecsTrace
+	logger       *Logger
+	client       *ecsClient
+	network      *ecsNetwork
+	trace        *ecsTrace
+	span         *ecsSpan
+	traceContext TraceContext
+	fields       map[string]interface{}
 }
 
 // Log creates a LogMessage at the specified level.
@@ -95,19 +105,22 @@ func (s *RequestScopedLogger) Log(level Level, format string, v ...interface{})
 			Log:       ecsLog{Level: LevelString(level)},
 			Service:   ecsService{Name: s.logger.serviceName},
 			Trace:     s.trace,
+			Span:      s.span,
+			Labels:    s.fields,
 		}
 	})
 }
 
 // Logger formats and delivers log messages (see: NewLogger()).
 type Logger struct {
-	writer      io.Writer
+	sink        Sink
 	serviceName string
 	logLevel    Level
 }
 
 // NewLogger creates a new Logger instance using arguments for an io.Writer, service name,
-// and log level.
+// and log level.  Messages are delivered as one JSON line per message, written to writer;
+// Use NewLoggerWithSink for other destinations (e.g. syslog).
 //
 // The serviceName argument corresponds to service.name in the ECS specification (see:
 // https://doc.wikimedia.org/ecs/#ecs-service).
@@ -115,26 +128,37 @@ type Logger struct {
 // The logLevel argument must be set to one of DEBUG, INFO, WARNING, ERROR, or FATAL.  Only
 // messages logged at this level -or higher- are formatted and output.
 func NewLogger(writer io.Writer, serviceName string, logLevel Level) (*Logger, error) {
+	return NewLoggerWithSink(NewWriterSink(writer), serviceName, logLevel)
+}
+
+// NewLoggerWithSink creates a new Logger instance that delivers messages to sink, using
+// the given service name and log level.
+//
+// This allows services to configure a production destination (e.g. SyslogSink), or a
+// MultiSink combining several destinations with independent levels (for example, syslog at
+// INFO in production, while still writing DEBUG to stdout in development).
+//
+// The serviceName and logLevel arguments are as documented for NewLogger.
+func NewLoggerWithSink(sink Sink, serviceName string, logLevel Level) (*Logger, error) {
 
 	if !validLevel(logLevel) {
 		return nil, fmt.Errorf("Unsupported log level: %d", logLevel)
 	}
 
-	return &Logger{writer: writer, serviceName: serviceName, logLevel: logLevel}, nil
+	return &Logger{sink: sink, serviceName: serviceName, logLevel: logLevel}, nil
 }
 
 // Request creates and returns a request-scoped Logger
 func (l *Logger) Request(r *http.Request) *RequestScopedLogger {
 	var err error
 	var forward string
-	var id string
 	var address string
 	var port string
 	var rsLog = &RequestScopedLogger{logger: l}
 
-	if id = r.Header.Get("X-Request-ID"); id != "" {
-		rsLog.trace = &ecsTrace{ID: id}
-	}
+	rsLog.traceContext = TraceContextFromRequest(r)
+	rsLog.trace = &ecsTrace{ID: rsLog.traceContext.TraceID, ParentID: rsLog.traceContext.ParentID}
+	rsLog.span = &ecsSpan{ID: rsLog.traceContext.SpanID}
 
 	if address, port, err = net.SplitHostPort(r.RemoteAddr); err == nil {
 		rsLog.client = &ecsClient{IP: address, Port: port}
@@ -212,22 +236,8 @@ func (l *Logger) log(level Level, msg func() LogMessage) {
 
 	message := msg()
 
-	str, err := json.Marshal(message)
-
-	// Handle the (unlikely) case where JSON serialization fails.
-	if err != nil {
-		l.send(fmt.Sprintf(`{"message": "Error serializing log message: %v (%s)", "service": {"name": "%s"}}`, message, err, l.serviceName))
-		return
-	}
-
-	// Log the messsage to the underlying io.Writer, one message per line.
-	l.send(string(str))
-}
-
-func (l *Logger) send(s string) {
-	// TODO: Should error handling be added to this? Our io.Writer will likely always be
-	// os.Stdout, what would we do if unable to write to stdout?
-	fmt.Fprintln(l.writer, s)
+	// Any error returned by the Sink is discarded.
+	l.sink.Emit(message)
 }
 
 // LevelString converts log integers to strings
@@ -255,3 +265,23 @@ func validLevel(level Level) bool {
 	}
 	return false
 }
+
+// levelFromString converts a LogMessage's level string (as produced by LevelString) back
+// into a Level, for use by Sinks (e.g. MultiSink, SyslogSink) that need to compare against
+// their own configured level, or map to a native severity.
+func levelFromString(level string) Level {
+	switch level {
+	case "DEBUG":
+		return DEBUG
+	case "INFO":
+		return INFO
+	case "WARNING":
+		return WARNING
+	case "ERROR":
+		return ERROR
+	case "FATAL":
+		return FATAL
+	default:
+		return INFO
+	}
+}
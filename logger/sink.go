@@ -0,0 +1,92 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Sink represents a destination for log messages.  A Logger delivers each LogMessage that
+// passes its configured level filter to its Sink (see: NewLoggerWithSink).
+type Sink interface {
+	// Emit delivers msg to the sink, returning an error if delivery fails.
+	Emit(msg LogMessage) error
+}
+
+// writerSink is a Sink that writes one JSON-encoded LogMessage per line to an io.Writer.
+// This is the Sink used by NewLogger.
+type writerSink struct {
+	writer io.Writer
+}
+
+// NewWriterSink returns a Sink that writes one JSON-encoded LogMessage per line to writer.
+func NewWriterSink(writer io.Writer) Sink {
+	return &writerSink{writer: writer}
+}
+
+func (s *writerSink) Emit(msg LogMessage) error {
+	str, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.writer, string(str))
+	return err
+}
+
+// sinkLevel pairs a Sink with the minimum Level of message it should receive.
+type sinkLevel struct {
+	sink  Sink
+	level Level
+}
+
+// MultiSink is a Sink that fans a LogMessage out to several other Sinks, each with its own
+// independent minimum level.  A failure delivering to one Sink never prevents delivery to
+// the others; Once all Sinks have been tried, Emit returns the first error encountered
+// (any later errors are discarded).
+type MultiSink struct {
+	sinks []sinkLevel
+}
+
+// NewMultiSink returns an empty MultiSink; Use Add to attach Sinks to it.
+func NewMultiSink() *MultiSink {
+	return &MultiSink{}
+}
+
+// Add attaches sink to the MultiSink, to receive messages at level or higher, and returns
+// the MultiSink so that calls may be chained.
+func (m *MultiSink) Add(sink Sink, level Level) *MultiSink {
+	m.sinks = append(m.sinks, sinkLevel{sink: sink, level: level})
+	return m
+}
+
+func (m *MultiSink) Emit(msg LogMessage) error {
+	var level = levelFromString(msg.Log.Level)
+	var firstErr error
+
+	for _, sl := range m.sinks {
+		if level < sl.level {
+			continue
+		}
+		if err := sl.sink.Emit(msg); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSink is a test-only Sink that records every LogMessage delivered to it, optionally
+// failing every delivery.
+type fakeSink struct {
+	received []LogMessage
+	failWith error
+}
+
+func (s *fakeSink) Emit(msg LogMessage) error {
+	s.received = append(s.received, msg)
+	return s.failWith
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	err := sink.Emit(LogMessage{Message: "hello", Log: ecsLog{Level: "INFO"}})
+	require.Nil(t, err)
+	assert.Contains(t, buf.String(), `"message":"hello"`)
+	assert.Contains(t, buf.String(), "\n")
+}
+
+func TestMultiSink(t *testing.T) {
+	t.Run("Delivers only to sinks at or above their configured level", func(t *testing.T) {
+		debugSink := &fakeSink{}
+		warningSink := &fakeSink{}
+
+		multi := NewMultiSink().Add(debugSink, DEBUG).Add(warningSink, WARNING)
+
+		require.Nil(t, multi.Emit(LogMessage{Message: "debug msg", Log: ecsLog{Level: "DEBUG"}}))
+		require.Nil(t, multi.Emit(LogMessage{Message: "warning msg", Log: ecsLog{Level: "WARNING"}}))
+
+		assert.Len(t, debugSink.received, 2, "debug-level sink should receive both messages")
+		assert.Len(t, warningSink.received, 1, "warning-level sink should only receive the warning message")
+		assert.Equal(t, "warning msg", warningSink.received[0].Message)
+	})
+
+	t.Run("A failing sink does not block delivery to the others", func(t *testing.T) {
+		failing := &fakeSink{failWith: errors.New("boom")}
+		healthy := &fakeSink{}
+
+		multi := NewMultiSink().Add(failing, DEBUG).Add(healthy, DEBUG)
+
+		err := multi.Emit(LogMessage{Message: "hi", Log: ecsLog{Level: "INFO"}})
+
+		assert.NotNil(t, err, "Emit should surface the failing sink's error")
+		assert.Len(t, failing.received, 1, "the failing sink should still have been tried")
+		assert.Len(t, healthy.received, 1, "the healthy sink should still receive the message")
+	})
+}
+
+func TestLevelFromString(t *testing.T) {
+	testCases := []struct {
+		str   string
+		level Level
+	}{
+		{"DEBUG", DEBUG},
+		{"INFO", INFO},
+		{"WARNING", WARNING},
+		{"ERROR", ERROR},
+		{"FATAL", FATAL},
+		{"garbage", INFO},
+	}
+
+	for _, tcase := range testCases {
+		assert.Equal(t, tcase.level, levelFromString(tcase.str), "levelFromString(%q)", tcase.str)
+	}
+}
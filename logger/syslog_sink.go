@@ -0,0 +1,71 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogSink is a Sink that delivers LogMessages (JSON-encoded, via Go's standard
+// log/syslog package) to a local or remote syslog daemon.  The wire format is the one
+// log/syslog writes ("<PRI>TIMESTAMP HOSTNAME TAG[PID]: MSG"); It is not RFC 5424 (there is
+// no version field, and no structured data).
+//
+// Example usage:
+//
+//	sink, _ := log.NewSyslogSink("", "", syslog.LOG_LOCAL0, "myservice")
+//	logger, _ := log.NewLoggerWithSink(sink, "myservice", log.INFO)
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink dials a syslog daemon (see: syslog.Dial) and returns a SyslogSink that
+// delivers messages there, tagged with tag, under facility.
+//
+// network and address are passed to syslog.Dial unmodified; Passing "", "" logs to the
+// local syslog daemon.
+func NewSyslogSink(network, address string, facility syslog.Priority, tag string) (*SyslogSink, error) {
+	writer, err := syslog.Dial(network, address, facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to connect to syslog: %s", err)
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+func (s *SyslogSink) Emit(msg LogMessage) error {
+	str, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	switch levelFromString(msg.Log.Level) {
+	case DEBUG:
+		return s.writer.Debug(string(str))
+	case INFO:
+		return s.writer.Info(string(str))
+	case WARNING:
+		return s.writer.Warning(string(str))
+	case ERROR:
+		return s.writer.Err(string(str))
+	case FATAL:
+		return s.writer.Crit(string(str))
+	default:
+		return s.writer.Info(string(str))
+	}
+}
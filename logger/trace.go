@@ -0,0 +1,109 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// TraceContext carries the identifiers of a W3C Trace Context (see:
+// https://www.w3.org/TR/trace-context/#traceparent-header) associated with a request.
+type TraceContext struct {
+	TraceID  string // 32 hex characters, shared by every span of the trace.
+	SpanID   string // 16 hex characters, identifying this request's own span.
+	ParentID string // 16 hex characters, identifying the span that called us; Empty if none.
+}
+
+type traceContextKeyType int
+
+// traceContextKey is the context.Context key under which the TraceContext for a request is
+// stored by LoggerInjectingMiddleware (see: InjectTraceContext).
+const traceContextKey traceContextKeyType = 0
+
+// traceParentRE matches a version 00 "traceparent" header value: version-traceid-spanid-flags.
+var traceParentRE = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// traceIDRE matches a conformant 32 lowercase-hex-character trace-id, as carried by the
+// "traceparent" header; See traceParentRE.
+var traceIDRE = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// spanIDRE matches a conformant 16 lowercase-hex-character span-id, as carried by the
+// "traceparent" header; See traceParentRE.
+var spanIDRE = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// TraceContextFromRequest derives the TraceContext for an inbound request.
+//
+// If r carries a well-formed "traceparent" header, its trace-id and span-id become
+// TraceID and ParentID respectively, and a new SpanID is generated for this request (so
+// that it becomes a child span of the caller). Otherwise, the legacy "X-Request-ID" header
+// is used as the TraceID if it is itself a conformant 32 hex-character id; A present but
+// non-conformant X-Request-ID (a UUID, say) cannot be forwarded as-is without producing an
+// invalid "traceparent" header downstream, so, as when neither header is present, a new
+// TraceID is synthesized. Either way, downstream services can continue the trace from the
+// returned TraceContext.
+func TraceContextFromRequest(r *http.Request) TraceContext {
+	var tc TraceContext
+
+	if matches := traceParentRE.FindStringSubmatch(r.Header.Get("traceparent")); matches != nil {
+		tc.TraceID = matches[1]
+		tc.ParentID = matches[2]
+	} else if id := r.Header.Get("X-Request-ID"); traceIDRE.MatchString(id) {
+		tc.TraceID = id
+	} else {
+		tc.TraceID = newHexID(16)
+	}
+
+	tc.SpanID = newHexID(8)
+
+	return tc
+}
+
+// InjectTraceContext sets the "traceparent" header on req from the TraceContext carried by
+// ctx (see: LoggerInjectingMiddleware), so that an outbound call (HTTP, gRPC, etc.) can be
+// correlated with the request that is causing it. It is a no-op if ctx carries no
+// TraceContext, or if the TraceContext's TraceID/SpanID are not well-formed (this shouldn't
+// happen for a TraceContext obtained via TraceContextFromRequest, but we do not want to
+// ever emit a "traceparent" header that violates our own traceParentRE).
+func InjectTraceContext(req *http.Request, ctx context.Context) {
+	tc, ok := ctx.Value(traceContextKey).(TraceContext)
+	if !ok {
+		return
+	}
+	if !traceIDRE.MatchString(tc.TraceID) || !spanIDRE.MatchString(tc.SpanID) {
+		return
+	}
+	req.Header.Set("traceparent", fmt.Sprintf("00-%s-%s-01", tc.TraceID, tc.SpanID))
+}
+
+// newHexID returns n random bytes, hex-encoded.
+func newHexID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand.Read only fails if the system's entropy source is unavailable; There
+		// isn't anything sensible to do but fall back to a zero ID. rand.Read makes no
+		// guarantee about partially-filled b on error, so zero it explicitly.
+		for i := range b {
+			b[i] = 0
+		}
+	}
+	return hex.EncodeToString(b)
+}
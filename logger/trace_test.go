@@ -0,0 +1,154 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceContextFromRequest(t *testing.T) {
+	t.Run("Parses a well-formed traceparent header", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+		tc := TraceContextFromRequest(r)
+
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", tc.TraceID)
+		assert.Equal(t, "b7ad6b7169203331", tc.ParentID)
+		assert.Len(t, tc.SpanID, 16, "a new span-id should be generated for this request")
+		assert.NotEqual(t, tc.ParentID, tc.SpanID)
+	})
+
+	t.Run("Falls back to X-Request-ID when traceparent is absent and X-Request-ID is a conformant trace-id", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Request-ID", "0af7651916cd43dd8448eb211c80319c")
+
+		tc := TraceContextFromRequest(r)
+
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", tc.TraceID)
+		assert.Empty(t, tc.ParentID)
+		assert.NotEmpty(t, tc.SpanID)
+	})
+
+	t.Run("Synthesizes a new trace-id when X-Request-ID is not a conformant trace-id", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Request-ID", "legacy-request-id")
+
+		tc := TraceContextFromRequest(r)
+
+		assert.Len(t, tc.TraceID, 32, "a non-hex request id cannot be forwarded as-is in a traceparent header")
+		assert.NotEqual(t, "legacy-request-id", tc.TraceID)
+		assert.Empty(t, tc.ParentID)
+	})
+
+	t.Run("Falls back to X-Request-ID when traceparent is malformed", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("traceparent", "not-a-real-traceparent-header")
+		r.Header.Set("X-Request-ID", "0af7651916cd43dd8448eb211c80319c")
+
+		tc := TraceContextFromRequest(r)
+
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", tc.TraceID)
+		assert.Empty(t, tc.ParentID)
+	})
+
+	t.Run("Synthesizes a new trace when neither header is present", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+
+		tc := TraceContextFromRequest(r)
+
+		assert.Len(t, tc.TraceID, 32)
+		assert.Empty(t, tc.ParentID)
+		assert.Len(t, tc.SpanID, 16)
+	})
+
+	t.Run("Two requests get distinct span ids", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+
+		first := TraceContextFromRequest(r)
+		second := TraceContextFromRequest(r)
+
+		assert.NotEqual(t, first.SpanID, second.SpanID)
+	})
+}
+
+func TestInjectTraceContext(t *testing.T) {
+	t.Run("Sets the traceparent header from the context", func(t *testing.T) {
+		tc := TraceContext{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331"}
+		ctx := context.WithValue(context.Background(), traceContextKey, tc)
+
+		req, err := http.NewRequest("GET", "http://example.org/", nil)
+		require.Nil(t, err)
+
+		InjectTraceContext(req, ctx)
+
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", req.Header.Get("traceparent"))
+	})
+
+	t.Run("Is a no-op when ctx carries no TraceContext", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "http://example.org/", nil)
+		require.Nil(t, err)
+
+		InjectTraceContext(req, context.Background())
+
+		assert.Empty(t, req.Header.Get("traceparent"))
+	})
+
+	t.Run("Is a no-op when the TraceContext's TraceID is not conformant", func(t *testing.T) {
+		tc := TraceContext{TraceID: "legacy-request-id", SpanID: "b7ad6b7169203331"}
+		ctx := context.WithValue(context.Background(), traceContextKey, tc)
+
+		req, err := http.NewRequest("GET", "http://example.org/", nil)
+		require.Nil(t, err)
+
+		InjectTraceContext(req, ctx)
+
+		assert.Empty(t, req.Header.Get("traceparent"))
+	})
+}
+
+func TestLoggerInjectingMiddlewarePropagatesTraceContext(t *testing.T) {
+	logger, _ := NewLogger(&mockWriter{}, "tracetest", DEBUG)
+
+	var injected context.Context
+	handler := LoggerInjectingMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		injected = r.Context()
+	}))
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	require.Nil(t, err)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	res, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	res.Body.Close()
+
+	outbound, err := http.NewRequest("GET", "http://downstream.example/", nil)
+	require.Nil(t, err)
+	InjectTraceContext(outbound, injected)
+
+	assert.Contains(t, outbound.Header.Get("traceparent"), "0af7651916cd43dd8448eb211c80319c")
+}
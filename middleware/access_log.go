@@ -0,0 +1,144 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	log "gerrit.wikimedia.org/r/mediawiki/services/servicelib-golang/logger"
+)
+
+// accessLogConfig holds the (optional) configuration for AccessLogMiddleware.
+type accessLogConfig struct {
+	skip func(status int) bool
+}
+
+// AccessLogOption configures an AccessLogMiddleware.
+type AccessLogOption func(*accessLogConfig)
+
+// SuppressSuccess skips access log entries for responses with a 2xx status code.  This is
+// useful for high-QPS internal services where successful responses would otherwise flood
+// the logs with noise.
+func SuppressSuccess() AccessLogOption {
+	return WithSkipPredicate(func(status int) bool {
+		return status >= 200 && status < 300
+	})
+}
+
+// WithSkipPredicate skips access log entries for responses where predicate returns true.
+func WithSkipPredicate(predicate func(status int) bool) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.skip = predicate
+	}
+}
+
+// accessLogObserver wraps a ResponseWriter in order to track the status code and number
+// of bytes written for later use, while passing through the optional interfaces (Hijacker,
+// Flusher, Pusher) that a wrapped handler may depend on (e.g. WebSocket upgrades, SSE).
+type accessLogObserver struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// Returns a new accessLogObserver with a default status.
+func newAccessLogObserver(w http.ResponseWriter) *accessLogObserver {
+	return &accessLogObserver{ResponseWriter: w, status: http.StatusOK}
+}
+
+// WriteHeader writes an HTTP response status code to the ResponseWriter and status observer.
+func (o *accessLogObserver) WriteHeader(code int) {
+	o.status = code
+	o.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes bytes to the ResponseWriter, and accumulates the count for later use.
+func (o *accessLogObserver) Write(b []byte) (int, error) {
+	n, err := o.ResponseWriter.Write(b)
+	o.bytes += n
+	return n, err
+}
+
+// Hijack implements http.Hijacker, passing through to the wrapped ResponseWriter so that
+// protocol upgrades (e.g. WebSockets) continue to work.
+func (o *accessLogObserver) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := o.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return hijacker.Hijack()
+}
+
+// Flush implements http.Flusher, passing through to the wrapped ResponseWriter so that
+// streaming responses (e.g. SSE) continue to work.
+func (o *accessLogObserver) Flush() {
+	if flusher, ok := o.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Push implements http.Pusher, passing through to the wrapped ResponseWriter if it
+// supports HTTP/2 server push.
+func (o *accessLogObserver) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := o.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return pusher.Push(target, opts)
+}
+
+// AccessLogMiddleware is an HTTP middleware that emits one structured (ECS) access log
+// line per request, via logger, including the method, path, status, bytes written,
+// duration, client IP, and trace ID.  The trace ID is the X-Request-ID header's value only
+// when that is a conformant 32 hex-character id (see: log.TraceContextFromRequest); Any
+// other X-Request-ID value is not logged.
+//
+// When used together with logger.LoggerInjectingMiddleware, the access log line shares
+// the request-scoped logger (and so the same trace ID) as the application's own log
+// messages for the request; Otherwise, a new request-scoped logger is created.
+//
+// By default every request is logged; Use SuppressSuccess or WithSkipPredicate to elide
+// uninteresting entries (e.g. 2xx responses on a high-QPS service).
+func AccessLogMiddleware(logger *log.Logger, opts ...AccessLogOption) func(http.Handler) http.Handler {
+	var cfg accessLogConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var start = time.Now()
+			var observer = newAccessLogObserver(w)
+
+			next.ServeHTTP(observer, r)
+
+			if cfg.skip != nil && cfg.skip(observer.status) {
+				return
+			}
+
+			reqLog, ok := r.Context().Value(log.ScopedLogger).(*log.RequestScopedLogger)
+			if !ok {
+				reqLog = logger.Request(r)
+			}
+
+			reqLog.Log(log.INFO, "%s %s %d %d %s", r.Method, r.URL.Path, observer.status, observer.bytes, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "gerrit.wikimedia.org/r/mediawiki/services/servicelib-golang/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWriter struct {
+	data []byte
+}
+
+func (m *mockWriter) Write(data []byte) (n int, err error) {
+	m.data = data
+	return len(m.data), nil
+}
+
+func (m *mockWriter) ReadMessage() (msg *log.LogMessage, err error) {
+	if err = json.Unmarshal(m.data, &msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	t.Run("Logs request details", func(t *testing.T) {
+		writer := &mockWriter{}
+		logger, _ := log.NewLogger(writer, "accesslogtest", log.DEBUG)
+
+		hello := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte("Hello World!"))
+		})
+
+		handler := AccessLogMiddleware(logger)(hello)
+		ts := httptest.NewServer(handler)
+		defer ts.Close()
+
+		res, err := http.Get(ts.URL)
+		require.Nil(t, err)
+		res.Body.Close()
+
+		msg, err := writer.ReadMessage()
+		require.Nil(t, err)
+		assert.Contains(t, msg.Message, "GET")
+		assert.Contains(t, msg.Message, "418")
+		assert.Contains(t, msg.Message, "12") // len("Hello World!")
+	})
+
+	t.Run("SuppressSuccess skips 2xx responses", func(t *testing.T) {
+		writer := &mockWriter{}
+		logger, _ := log.NewLogger(writer, "accesslogtest", log.DEBUG)
+
+		hello := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		})
+
+		handler := AccessLogMiddleware(logger, SuppressSuccess())(hello)
+		ts := httptest.NewServer(handler)
+		defer ts.Close()
+
+		res, err := http.Get(ts.URL)
+		require.Nil(t, err)
+		res.Body.Close()
+
+		assert.Equal(t, 0, len(writer.data), "Unexpected log output for a suppressed 2xx response")
+	})
+
+	t.Run("SuppressSuccess still logs non-2xx responses", func(t *testing.T) {
+		writer := &mockWriter{}
+		logger, _ := log.NewLogger(writer, "accesslogtest", log.DEBUG)
+
+		broken := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		handler := AccessLogMiddleware(logger, SuppressSuccess())(broken)
+		ts := httptest.NewServer(handler)
+		defer ts.Close()
+
+		res, err := http.Get(ts.URL)
+		require.Nil(t, err)
+		res.Body.Close()
+
+		assert.NotEqual(t, 0, len(writer.data), "Expected log output for a 5xx response")
+	})
+}
+
+// recordingHijacker is an http.ResponseWriter that also implements http.Hijacker, so that
+// accessLogObserver's passthrough can be exercised without a real network connection.
+type recordingHijacker struct {
+	http.ResponseWriter
+	hijacked bool
+}
+
+func (h *recordingHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	return nil, nil, nil
+}
+
+func TestAccessLogObserver(t *testing.T) {
+	t.Run("Counts bytes written", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		observer := newAccessLogObserver(rec)
+
+		n, err := observer.Write([]byte("hello"))
+		require.Nil(t, err)
+		assert.Equal(t, 5, n)
+		assert.Equal(t, 5, observer.bytes)
+
+		n, err = observer.Write([]byte(" world"))
+		require.Nil(t, err)
+		assert.Equal(t, 6, n)
+		assert.Equal(t, 11, observer.bytes)
+	})
+
+	t.Run("Tracks the written status code", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		observer := newAccessLogObserver(rec)
+
+		observer.WriteHeader(http.StatusNotFound)
+		assert.Equal(t, http.StatusNotFound, observer.status)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Defaults to 200 OK", func(t *testing.T) {
+		observer := newAccessLogObserver(httptest.NewRecorder())
+		assert.Equal(t, http.StatusOK, observer.status)
+	})
+
+	t.Run("Hijack passes through to the wrapped ResponseWriter", func(t *testing.T) {
+		hijacker := &recordingHijacker{ResponseWriter: httptest.NewRecorder()}
+		observer := newAccessLogObserver(hijacker)
+
+		_, _, err := observer.Hijack()
+		require.Nil(t, err)
+		assert.True(t, hijacker.hijacked)
+	})
+
+	t.Run("Hijack fails when the wrapped ResponseWriter doesn't support it", func(t *testing.T) {
+		observer := newAccessLogObserver(httptest.NewRecorder())
+		_, _, err := observer.Hijack()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Flush passes through to the wrapped ResponseWriter", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		observer := newAccessLogObserver(rec)
+		observer.Write([]byte("flushed"))
+		observer.Flush()
+		assert.True(t, rec.Flushed)
+	})
+}
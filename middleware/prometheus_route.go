@@ -0,0 +1,115 @@
+/*
+ * Copyright 2026 Wikimedia Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// routeConfig holds the (optional) configuration for PrometheusInstrumentationMiddlewareWithRoute.
+type routeConfig struct {
+	excludePaths []string
+}
+
+// RouteOption configures a PrometheusInstrumentationMiddlewareWithRoute.
+type RouteOption func(*routeConfig)
+
+// unmatchedRouteLabel is the "handler" label value used by MuxRouteName and
+// ServeMuxRouteName when a request matches no route/pattern, in place of the concrete
+// request path; See those functions for why.
+const unmatchedRouteLabel = "other"
+
+// ExcludePaths excludes the given request paths (e.g. health checks) from instrumentation
+// entirely, so that they don't dominate the latency histograms.
+func ExcludePaths(paths ...string) RouteOption {
+	return func(c *routeConfig) {
+		c.excludePaths = append(c.excludePaths, paths...)
+	}
+}
+
+// PrometheusInstrumentationMiddlewareWithRoute is a variant of
+// PrometheusInstrumentationMiddleware that adds a third "handler" label, populated by
+// routeFn with the matched route template (e.g. "/page/{title}") rather than the concrete
+// request path, avoiding the unbounded cardinality that labeling by path would otherwise
+// create.
+//
+// See MuxRouteName and ServeMuxRouteName for routeFn implementations covering gorilla/mux
+// and net/http's 1.22+ ServeMux pattern matching, respectively.
+func PrometheusInstrumentationMiddlewareWithRoute(reqCounter *prometheus.CounterVec, latencyHist *prometheus.HistogramVec, routeFn func(*http.Request) string, opts ...RouteOption) func(http.Handler) http.Handler {
+	var cfg routeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, excluded := range cfg.excludePaths {
+				if r.URL.Path == excluded {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			var start time.Time = time.Now()
+			var observer *statusObserver = newStatusObserver(w)
+
+			next.ServeHTTP(observer, r)
+
+			var status string = strconv.Itoa(observer.status)
+			var route string = routeFn(r)
+
+			latencyHist.WithLabelValues(status, r.Method, route).Observe(time.Since(start).Seconds())
+			reqCounter.WithLabelValues(status, r.Method, route).Inc()
+		})
+	}
+}
+
+// MuxRouteName is a routeFn, suitable for use with PrometheusInstrumentationMiddlewareWithRoute,
+// that returns the path template of the matched gorilla/mux route (e.g. "/page/{title}"),
+// or unmatchedRouteLabel if the request matched no mux.Router route with a path template
+// (route names are not consulted). The literal
+// request path is deliberately never used here: an unmatched request (a 404, a scanner
+// probing arbitrary paths, ...) would otherwise produce a distinct "handler" label value per
+// path, reintroducing the unbounded cardinality this middleware exists to avoid.
+func MuxRouteName(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return unmatchedRouteLabel
+}
+
+// ServeMuxRouteName returns a routeFn, suitable for use with
+// PrometheusInstrumentationMiddlewareWithRoute, that returns the matched pattern (e.g.
+// "/page/{title}") for a request routed by serveMux, using net/http 1.22's enhanced
+// ServeMux pattern matching (see: http.ServeMux.Handler), or unmatchedRouteLabel if
+// serveMux has no matching pattern. As with MuxRouteName, the literal request path is
+// deliberately never used for the no-match case, to avoid unbounded label cardinality.
+func ServeMuxRouteName(serveMux *http.ServeMux) func(*http.Request) string {
+	return func(r *http.Request) string {
+		if _, pattern := serveMux.Handler(r); pattern != "" {
+			return pattern
+		}
+		return unmatchedRouteLabel
+	}
+}
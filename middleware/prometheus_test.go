@@ -115,3 +115,97 @@ func TestPrometheusInstrumentationMiddleware(t *testing.T) {
 	require.Nil(t, scanner.Err())
 	t.FailNow()
 }
+
+func TestPrometheusInstrumentationMiddlewareWithRoute(t *testing.T) {
+	var (
+		reqCounter = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_requests_total_with_route",
+				Help: "Count of HTTP requests processed, partitioned by status code, HTTP method, and route.",
+			},
+			[]string{"code", "method", "handler"},
+		)
+
+		durationHisto = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "http_request_duration_seconds_with_route",
+				Help:    "A histogram of latencies for requests, partitioned by status code, HTTP method, and route.",
+				Buckets: []float64{.001, .0025, .0050, .01, .025, .050, .10, .25, .50, 1},
+			},
+			[]string{"code", "method", "handler"},
+		)
+	)
+
+	prometheus.MustRegister(reqCounter, durationHisto)
+
+	// Hello, world
+	hello := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html><body>Hello World!</body></html>")
+	})
+
+	routeFn := func(r *http.Request) string {
+		return "/hello"
+	}
+
+	// Wrap our handler function in middleware; health checks are excluded entirely.
+	handler := PrometheusInstrumentationMiddlewareWithRoute(reqCounter, durationHisto, routeFn, ExcludePaths("/healthz"))(hello)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// A health check should not be instrumented at all.
+	_, err := http.Get(server.URL + "/healthz")
+	require.Nil(t, err)
+
+	// Make a request to generate some metrics
+	_, err = http.Get(server.URL)
+	require.Nil(t, err)
+
+	metrics := httptest.NewServer(promhttp.Handler())
+	defer metrics.Close()
+
+	// Make a request against the prometheus handler, to read the metrics
+	res, err := http.Get(metrics.URL)
+	require.Nil(t, err)
+	defer res.Body.Close()
+
+	// The output we're looking for should be something like:
+	//
+	//   ...
+	//   http_requests_total_with_route{code="200",handler="/hello",method="GET"} 1
+	//   http_request_duration_seconds_with_route_count{code="200",handler="/hello",method="GET"} 1
+	//   ...
+	var i int = 0
+	var scanner *bufio.Scanner
+	var statusOk, methodGet, routeHello *regexp.Regexp
+
+	statusOk = regexp.MustCompile(`code="200"`)
+	methodGet = regexp.MustCompile(`method="GET"`)
+	routeHello = regexp.MustCompile(`handler="/hello"`)
+
+	scanner = bufio.NewScanner(res.Body)
+
+	// Process the output line-by-line
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Match where code=200, method=GET, and handler=/hello
+		if statusOk.MatchString(line) && methodGet.MatchString(line) && routeHello.MatchString(line) {
+			// Match the counter and histogram metrics
+			if strings.HasPrefix(line, "http_requests_total_with_route") || strings.HasPrefix(line, "http_request_duration_seconds_with_route_count") {
+				// The total for each count should be exactly: 1
+				if strings.HasSuffix(line, "1") {
+					i++
+				}
+			}
+		}
+
+		// Two matches is win.
+		if i > 1 {
+			return
+		}
+	}
+
+	// If we get here, something has gone wrong.
+	require.Nil(t, scanner.Err())
+	t.FailNow()
+}